@@ -0,0 +1,20 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package about exposes build and runtime information about the operator binary.
+package about
+
+// BuildInfo holds versioning information baked into the operator binary at build time.
+type BuildInfo struct {
+	Version string
+	Hash    string
+	Date    string
+}
+
+// OperatorInfo describes the running operator instance.
+type OperatorInfo struct {
+	BuildInfo         BuildInfo
+	CustomOperatorUID string
+	Distribution      string
+}
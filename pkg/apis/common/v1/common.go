@@ -0,0 +1,24 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1
+
+// ObjectSelector defines a reference to a Kubernetes object which can be, but does not have to be, managed by ECK.
+type ObjectSelector struct {
+	// Name of the Kubernetes object.
+	Name string `json:"name,omitempty"`
+	// Namespace of the Kubernetes object. If empty, defaults to the current namespace.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// IsDefined returns true if the object selector is not nil and not empty.
+func (o ObjectSelector) IsDefined() bool {
+	return o != ObjectSelector{}
+}
+
+// ConfigSource references a Secret holding configuration to merge into the computed one.
+type ConfigSource struct {
+	// SecretName is the name of a Kubernetes secret in the same namespace as the resource.
+	SecretName string `json:"secretName"`
+}
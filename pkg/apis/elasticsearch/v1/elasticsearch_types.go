@@ -0,0 +1,83 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package v1 contains API schema definitions for managing Elasticsearch resources.
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// RemoteCluster declares an intention to configure a remote cluster to another Elasticsearch cluster,
+// identified by name, that this Elasticsearch resource should establish a cross-cluster connection to.
+type RemoteCluster struct {
+	// Name identifies the remote cluster, both in this spec and in the Elasticsearch remote cluster settings.
+	Name string `json:"name"`
+}
+
+// ElasticsearchSpec holds the desired state of an Elasticsearch resource.
+type ElasticsearchSpec struct {
+	// Version of Elasticsearch.
+	Version string `json:"version"`
+	// RemoteClusters lists the other Elasticsearch clusters this cluster should be connected to.
+	RemoteClusters []RemoteCluster `json:"remoteClusters,omitempty"`
+}
+
+// ElasticsearchStatus defines the observed state of an Elasticsearch resource.
+type ElasticsearchStatus struct {
+	// AvailableNodes is the number of available replicas in the Elasticsearch cluster.
+	AvailableNodes int32 `json:"availableNodes,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Elasticsearch is a Kubernetes CRD to represent Elasticsearch.
+type Elasticsearch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchSpec   `json:"spec,omitempty"`
+	Status ElasticsearchStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchList contains a list of Elasticsearch resources.
+type ElasticsearchList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Elasticsearch `json:"items"`
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *Elasticsearch) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(Elasticsearch)
+	*out = *in
+	out.ObjectMeta = *in.ObjectMeta.DeepCopy()
+	if in.Spec.RemoteClusters != nil {
+		out.Spec.RemoteClusters = append([]RemoteCluster(nil), in.Spec.RemoteClusters...)
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ElasticsearchList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchList)
+	*out = *in
+	out.ListMeta = *in.ListMeta.DeepCopy()
+	if in.Items != nil {
+		out.Items = make([]Elasticsearch, len(in.Items))
+		for i := range in.Items {
+			out.Items[i] = *in.Items[i].DeepCopyObject().(*Elasticsearch)
+		}
+	}
+	return out
+}
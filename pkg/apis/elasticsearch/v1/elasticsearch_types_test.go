@@ -0,0 +1,26 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestElasticsearch_DeepCopyObject_DoesNotAliasRemoteClusters(t *testing.T) {
+	in := Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: ElasticsearchSpec{
+			RemoteClusters: []RemoteCluster{{Name: "cluster-a"}},
+		},
+	}
+
+	out := in.DeepCopyObject().(*Elasticsearch)
+	out.Spec.RemoteClusters[0].Name = "cluster-b"
+
+	require.Equal(t, "cluster-a", in.Spec.RemoteClusters[0].Name, "mutating the copy must not affect the original")
+}
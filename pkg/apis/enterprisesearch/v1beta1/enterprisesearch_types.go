@@ -0,0 +1,117 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package v1beta1 contains API schema definitions for managing Enterprise Search resources.
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+)
+
+// HTTPConfig holds the HTTP layer configuration for Enterprise Search, in particular the TLS settings.
+type HTTPConfig struct {
+	// TLS allows providing a user-defined certificate chain, instead of the default self-signed one.
+	TLS TLSOptions `json:"tls,omitempty"`
+}
+
+// TLSOptions allows a user to reference their own HTTP certificate secret instead of the operator-managed one.
+type TLSOptions struct {
+	// Certificate references a Secret holding the certificate chain and private key for the HTTP layer.
+	Certificate commonv1.ObjectSelector `json:"certificate,omitempty"`
+}
+
+// EnterpriseSearchSpec holds the desired state of an Enterprise Search resource.
+type EnterpriseSearchSpec struct {
+	// Version of Enterprise Search.
+	Version string `json:"version"`
+	// Count of Enterprise Search replicas to deploy.
+	Count int32 `json:"count,omitempty"`
+	// ElasticsearchRef is a reference to the Elasticsearch cluster backing this Enterprise Search deployment.
+	ElasticsearchRef commonv1.ObjectSelector `json:"elasticsearchRef,omitempty"`
+	// ConfigRef references a Secret holding the Enterprise Search configuration to merge in.
+	ConfigRef commonv1.ConfigSource `json:"configRef,omitempty"`
+	// HTTP holds the HTTP layer configuration for Enterprise Search.
+	HTTP HTTPConfig `json:"http,omitempty"`
+	// Metrics holds the metrics exposure configuration for Enterprise Search.
+	Metrics MetricsConfig `json:"metrics,omitempty"`
+}
+
+// MetricsConfig holds the metrics exposure configuration for Enterprise Search.
+type MetricsConfig struct {
+	// AuthProxy, once enabled, fronts the Enterprise Search metrics/HTTP endpoint with an oauth-proxy
+	// sidecar that delegates AuthN/AuthZ to the Kubernetes API server, so Prometheus can scrape it without
+	// embedding Elasticsearch credentials.
+	AuthProxy AuthProxyConfig `json:"authProxy,omitempty"`
+}
+
+// AuthProxyConfig configures the oauth-proxy sidecar protecting the Enterprise Search HTTP endpoint.
+//
+// The sidecar terminates TLS with a serving certificate the operator does not generate itself: it only
+// sets the service.beta.openshift.io/serving-cert-secret-name annotation on the Service, which OpenShift
+// acts on. On other clusters nothing in the operator creates the Secret the sidecar mounts its certificate
+// from, and the Pods do not start until that Secret exists. The operator creates no cert-manager Certificate.
+type AuthProxyConfig struct {
+	// Enabled toggles the oauth-proxy sidecar.
+	Enabled bool `json:"enabled,omitempty"`
+	// Resource is the name of the resource the proxy checks access to via a Kubernetes SubjectAccessReview
+	// (in the EnterpriseSearch API group), defaulting to the name of this EnterpriseSearch resource.
+	Resource string `json:"resource,omitempty"`
+}
+
+// EnterpriseSearchStatus defines the observed state of an Enterprise Search resource.
+type EnterpriseSearchStatus struct {
+	// AvailableNodes is the number of available replicas of the Enterprise Search Deployment.
+	AvailableNodes int32 `json:"availableNodes,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EnterpriseSearch is a Kubernetes CRD to represent Enterprise Search.
+type EnterpriseSearch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EnterpriseSearchSpec   `json:"spec,omitempty"`
+	Status EnterpriseSearchStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EnterpriseSearchList contains a list of EnterpriseSearch resources.
+type EnterpriseSearchList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EnterpriseSearch `json:"items"`
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *EnterpriseSearch) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(EnterpriseSearch)
+	*out = *in
+	out.ObjectMeta = *in.ObjectMeta.DeepCopy()
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *EnterpriseSearchList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(EnterpriseSearchList)
+	*out = *in
+	out.ListMeta = *in.ListMeta.DeepCopy()
+	if in.Items != nil {
+		out.Items = make([]EnterpriseSearch, len(in.Items))
+		for i := range in.Items {
+			out.Items[i] = *in.Items[i].DeepCopyObject().(*EnterpriseSearch)
+		}
+	}
+	return out
+}
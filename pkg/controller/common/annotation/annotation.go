@@ -0,0 +1,11 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package annotation defines annotation keys shared across ECK controllers.
+package annotation
+
+const (
+	// ControllerVersionAnnotation is set on managed resources to the version of the operator that last reconciled them.
+	ControllerVersionAnnotation = "common.k8s.elastic.co/controller-version"
+)
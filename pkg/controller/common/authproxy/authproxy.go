@@ -0,0 +1,168 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package authproxy provides a reusable oauth-proxy sidecar that fronts an operator-managed HTTP
+// endpoint, terminating TLS with a Kubernetes serving certificate and delegating AuthN/AuthZ to the
+// Kubernetes API server via TokenReview/SubjectAccessReview, instead of embedding credentials of the
+// proxied application itself.
+package authproxy
+
+import (
+	"encoding/json"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ContainerName is the name of the oauth-proxy sidecar container.
+	ContainerName = "auth-proxy"
+	// Image is the oauth-proxy image injected as a sidecar.
+	Image = "quay.io/openshift/origin-oauth-proxy:latest"
+
+	// OpenShiftServingCertAnnotation, when set on the proxy Service, asks OpenShift to mint and maintain a
+	// serving certificate Secret for it.
+	OpenShiftServingCertAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+)
+
+// Config describes the resource an oauth-proxy sidecar protects, and how to authorize access to it.
+type Config struct {
+	// Namespace and Name identify the owning resource, used to name the ServiceAccount and RBAC objects.
+	Namespace string
+	Name      string
+	// Labels are applied to every object created for this proxy.
+	Labels map[string]string
+	// ServingCertSecretName names the Secret holding the TLS certificate the proxy terminates with.
+	ServingCertSecretName string
+	// UpstreamPort is the port of the container the proxy forwards authorized requests to.
+	UpstreamPort int32
+	// ProxyPort is the port the proxy itself listens on.
+	ProxyPort int32
+	// ResourceAttribute is the apiGroup/resource/name checked through a SubjectAccessReview, granting
+	// access to anyone allowed to `get` it.
+	ResourceAttribute ResourceAttribute
+}
+
+// ResourceAttribute identifies the Kubernetes resource a SubjectAccessReview is issued against.
+type ResourceAttribute struct {
+	APIGroup string
+	Resource string
+	Name     string
+}
+
+// sarJSON renders the ResourceAttribute as the JSON object oauth-proxy's --openshift-sar flag expects,
+// scoping the SubjectAccessReview to this specific resource rather than every resource of that type.
+func (r ResourceAttribute) sarJSON() string {
+	// field names match oauth-proxy's expected SAR attributes: group/resource/resourceName/verb.
+	attrs := struct {
+		Group        string `json:"group"`
+		Resource     string `json:"resource"`
+		ResourceName string `json:"resourceName"`
+		Verb         string `json:"verb"`
+	}{
+		Group:        r.APIGroup,
+		Resource:     r.Resource,
+		ResourceName: r.Name,
+		Verb:         "get",
+	}
+	// marshaling a fixed, non-user-controlled struct never fails.
+	encoded, _ := json.Marshal(attrs)
+	return string(encoded)
+}
+
+// ServiceAccountName returns the name of the ServiceAccount the oauth-proxy sidecar runs as.
+func (c Config) ServiceAccountName() string {
+	return c.Name + "-auth-proxy"
+}
+
+// ClusterRoleName returns the name of the ClusterRole granting TokenReview/SubjectAccessReview access.
+func (c Config) ClusterRoleName() string {
+	return c.Namespace + "-" + c.Name + "-auth-proxy"
+}
+
+// Container builds the oauth-proxy sidecar container for the given configuration.
+func Container(cfg Config) corev1.Container {
+	return corev1.Container{
+		Name:  ContainerName,
+		Image: Image,
+		Args: []string{
+			"--https-address=:" + strconv.Itoa(int(cfg.ProxyPort)),
+			"--upstream=http://localhost:" + strconv.Itoa(int(cfg.UpstreamPort)),
+			"--tls-cert=/etc/tls/private/tls.crt",
+			"--tls-key=/etc/tls/private/tls.key",
+			"--openshift-service-account=" + cfg.ServiceAccountName(),
+			"--openshift-sar=" + cfg.ResourceAttribute.sarJSON(),
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "https", ContainerPort: cfg.ProxyPort, Protocol: corev1.ProtocolTCP},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "auth-proxy-tls", MountPath: "/etc/tls/private", ReadOnly: true},
+		},
+	}
+}
+
+// Volume builds the Volume backing the proxy's serving certificate VolumeMount.
+func Volume(cfg Config) corev1.Volume {
+	return corev1.Volume{
+		Name: "auth-proxy-tls",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: cfg.ServingCertSecretName},
+		},
+	}
+}
+
+// ServiceAccount builds the ServiceAccount the oauth-proxy sidecar runs as.
+func ServiceAccount(cfg Config) corev1.ServiceAccount {
+	return corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cfg.Namespace,
+			Name:      cfg.ServiceAccountName(),
+			Labels:    cfg.Labels,
+		},
+	}
+}
+
+// ClusterRole builds the ClusterRole granting the `create` verb on tokenreviews and subjectaccessreviews,
+// the minimal RBAC an oauth-proxy sidecar needs to delegate AuthN/AuthZ to the API server.
+func ClusterRole(cfg Config) rbacv1.ClusterRole {
+	return rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   cfg.ClusterRoleName(),
+			Labels: cfg.Labels,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"authentication.k8s.io"},
+				Resources: []string{"tokenreviews"},
+				Verbs:     []string{"create"},
+			},
+			{
+				APIGroups: []string{"authorization.k8s.io"},
+				Resources: []string{"subjectaccessreviews"},
+				Verbs:     []string{"create"},
+			},
+		},
+	}
+}
+
+// ClusterRoleBinding binds the ClusterRole above to the proxy's ServiceAccount.
+func ClusterRoleBinding(cfg Config) rbacv1.ClusterRoleBinding {
+	return rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   cfg.ClusterRoleName(),
+			Labels: cfg.Labels,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     cfg.ClusterRoleName(),
+		},
+		Subjects: []rbacv1.Subject{
+			{Kind: rbacv1.ServiceAccountKind, Namespace: cfg.Namespace, Name: cfg.ServiceAccountName()},
+		},
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package common holds conventions shared by all ECK controllers.
+package common
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+const (
+	// ManagedAnnotation, when set to "false", tells the operator to ignore a resource.
+	ManagedAnnotation = "common.k8s.elastic.co/controller-managed"
+)
+
+// IsUnmanaged returns true if the given object is annotated to be ignored by the operator.
+func IsUnmanaged(object metav1.Object) bool {
+	return object.GetAnnotations()[ManagedAnnotation] == "false"
+}
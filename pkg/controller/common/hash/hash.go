@@ -0,0 +1,28 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package hash provides a stable hashing helper used to detect drift between the desired and live state
+// of Kubernetes objects (e.g. to trigger a rolling restart when a mounted Secret changes).
+package hash
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// HashObject computes a hash of the given object, suitable for use as a label or annotation value
+// to detect changes to that object over time.
+func HashObject(object interface{}) string {
+	hasher := fnv.New32a()
+	printer := spew.ConfigState{
+		Indent:         " ",
+		SortKeys:       true,
+		DisableMethods: true,
+		SpewKeys:       true,
+	}
+	printer.Fprintf(hasher, "%#v", object)
+	return fmt.Sprint(hasher.Sum32())
+}
@@ -0,0 +1,14 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package operator holds configuration shared by all the operator's controllers.
+package operator
+
+import "github.com/elastic/cloud-on-k8s/pkg/about"
+
+// Parameters are the parameters passed down to every controller at startup.
+type Parameters struct {
+	// OperatorInfo describes the running operator instance.
+	OperatorInfo about.OperatorInfo
+}
@@ -0,0 +1,134 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package reconciler
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/ohler55/ojg/jp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// ManagedFields is the list of JSONPath expressions (e.g. "$.spec.replicas", "$.data") describing the
+// subtrees of a resource this operator owns. ReconcileResource only converges those subtrees towards the
+// expected object, leaving everything else on the live object untouched: a ClusterIP assigned by
+// Kubernetes, a field defaulted by the API server, an annotation added by a mutating webhook. This
+// replaces bespoke per-field reflect.DeepEqual comparisons with a single declarative list per resource.
+type ManagedFields []string
+
+// ReconcileResource creates expected if it does not exist yet, or updates exactly the subtrees named by
+// fields on the live object if they have drifted from expected. reconciled must be a pointer to the same
+// concrete type as expected; it is populated with the resulting state of the cluster.
+func ReconcileResource(client k8s.Client, key types.NamespacedName, expected, reconciled runtime.Object, fields ManagedFields) error {
+	err := client.Get(key, reconciled)
+	if err != nil && apierrors.IsNotFound(err) {
+		if err := copyInto(expected, reconciled); err != nil {
+			return err
+		}
+		return client.Create(reconciled)
+	} else if err != nil {
+		return err
+	}
+
+	changed, err := mergeManagedFields(expected, reconciled, fields)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	return client.Update(reconciled)
+}
+
+// mergeManagedFields projects expected's and live's fields, and writes back into live every path whose
+// projected value differs from expected, returning whether live was modified.
+func mergeManagedFields(expected, live interface{}, fields ManagedFields) (bool, error) {
+	expectedGeneric, err := toGeneric(expected)
+	if err != nil {
+		return false, err
+	}
+	liveGeneric, err := toGeneric(live)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	for _, path := range fields {
+		expr, err := jp.ParseString(path)
+		if err != nil {
+			return false, err
+		}
+		expectedMatches := expr.Get(expectedGeneric)
+		liveMatches := expr.Get(liveGeneric)
+		expectedValue := first(expectedMatches)
+		liveValue := first(liveMatches)
+		if reflect.DeepEqual(expectedValue, liveValue) {
+			continue
+		}
+		if len(expectedMatches) == 0 {
+			// expected no longer has anything at this path (e.g. an annotation key that was removed):
+			// delete it from live instead of Set-ing a JSON null over it, which would round-trip back as
+			// an empty string rather than an absent key.
+			if len(liveMatches) == 0 {
+				continue
+			}
+			if err := expr.Del(liveGeneric); err != nil {
+				return false, err
+			}
+		} else if err := expr.Set(liveGeneric, expectedValue); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+
+	return true, fromGeneric(liveGeneric, live)
+}
+
+func first(values []interface{}) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+// toGeneric decodes a concrete Kubernetes object into the generic interface{} tree the jp package
+// operates on.
+func toGeneric(obj interface{}) (interface{}, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// fromGeneric writes a generic interface{} tree back into a concrete Kubernetes object.
+func fromGeneric(generic interface{}, obj interface{}) error {
+	raw, err := json.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, obj)
+}
+
+// copyInto copies src's fields into dst, which must point to the same concrete type.
+func copyInto(src, dst interface{}) error {
+	raw, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, dst)
+}
@@ -0,0 +1,139 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+var serviceManagedFields = ManagedFields{"$.spec.selector", "$.spec.ports"}
+
+func TestReconcileResource_PreservesForeignFieldsOnUpdate(t *testing.T) {
+	key := types.NamespacedName{Namespace: "ns", Name: "svc"}
+
+	// the live object carries fields this operator does not own: a webhook-added annotation, a
+	// field defaulted by the API server (Type), and a ClusterIP assigned by Kubernetes itself.
+	live := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "svc",
+			Annotations: map[string]string{"webhook.example.com/injected": "true"},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			ClusterIP: "10.0.0.42",
+			Selector:  map[string]string{"app": "old"},
+			Ports:     []corev1.ServicePort{{Name: "https", Port: 3002, TargetPort: intstr.FromInt(3002)}},
+		},
+	}
+
+	client := k8s.WrappedFakeClient(&live)
+
+	// the desired state only disagrees with live on the fields this operator owns.
+	expected := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "new"},
+			Ports:    []corev1.ServicePort{{Name: "https", Port: 3002, TargetPort: intstr.FromInt(4443)}},
+		},
+	}
+
+	var reconciled corev1.Service
+	err := ReconcileResource(client, key, &expected, &reconciled, serviceManagedFields)
+	require.NoError(t, err)
+
+	// owned fields converged
+	require.Equal(t, map[string]string{"app": "new"}, reconciled.Spec.Selector)
+	require.Equal(t, intstr.FromInt(4443), reconciled.Spec.Ports[0].TargetPort)
+
+	// foreign fields survived untouched
+	require.Equal(t, "10.0.0.42", reconciled.Spec.ClusterIP)
+	require.Equal(t, corev1.ServiceTypeClusterIP, reconciled.Spec.Type)
+	require.Equal(t, "true", reconciled.Annotations["webhook.example.com/injected"])
+
+	// fetching it back from the cluster should reflect the same merge
+	var stored corev1.Service
+	require.NoError(t, client.Get(key, &stored))
+	require.Equal(t, map[string]string{"app": "new"}, stored.Spec.Selector)
+	require.Equal(t, "10.0.0.42", stored.Spec.ClusterIP)
+}
+
+func TestReconcileResource_CreatesWhenMissing(t *testing.T) {
+	key := types.NamespacedName{Namespace: "ns", Name: "svc"}
+	client := k8s.WrappedFakeClient()
+
+	expected := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "new"},
+		},
+	}
+
+	var reconciled corev1.Service
+	err := ReconcileResource(client, key, &expected, &reconciled, serviceManagedFields)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"app": "new"}, reconciled.Spec.Selector)
+
+	var stored corev1.Service
+	require.NoError(t, client.Get(key, &stored))
+	require.Equal(t, map[string]string{"app": "new"}, stored.Spec.Selector)
+}
+
+func TestReconcileResource_NoopWhenConverged(t *testing.T) {
+	key := types.NamespacedName{Namespace: "ns", Name: "svc"}
+	live := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "same"}},
+	}
+	client := k8s.WrappedFakeClient(&live)
+
+	expected := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "same"}},
+	}
+
+	var reconciled corev1.Service
+	err := ReconcileResource(client, key, &expected, &reconciled, serviceManagedFields)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"app": "same"}, reconciled.Spec.Selector)
+}
+
+func TestReconcileResource_DeletesManagedAnnotationWhenNoLongerExpected(t *testing.T) {
+	key := types.NamespacedName{Namespace: "ns", Name: "svc"}
+	annotationPath := ManagedFields{"$.metadata.annotations['example.com/managed']"}
+
+	live := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "svc",
+			Annotations: map[string]string{"example.com/managed": "on", "example.com/foreign": "untouched"},
+		},
+	}
+	client := k8s.WrappedFakeClient(&live)
+
+	// expected no longer carries the managed annotation at all.
+	expected := corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc"}}
+
+	var reconciled corev1.Service
+	err := ReconcileResource(client, key, &expected, &reconciled, annotationPath)
+	require.NoError(t, err)
+
+	_, stillPresent := reconciled.Annotations["example.com/managed"]
+	require.False(t, stillPresent, "managed annotation should be removed, not set to an empty string")
+	require.Equal(t, "untouched", reconciled.Annotations["example.com/foreign"])
+
+	var stored corev1.Service
+	require.NoError(t, client.Get(key, &stored))
+	_, stillPresent = stored.Annotations["example.com/managed"]
+	require.False(t, stillPresent)
+}
@@ -0,0 +1,27 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package reconciler holds helpers shared by ECK controllers to converge a live object towards an
+// expected one.
+package reconciler
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// secretManagedFields are the only subtrees of a Secret this operator ever owns: its payload and labels.
+// Everything else - annotations added by another controller, owner references, resourceVersion - is left
+// as-is on the live object.
+var secretManagedFields = ManagedFields{"$.data", "$.metadata.labels"}
+
+// ReconcileSecret creates the expected Secret if it does not exist yet, or updates its Data and Labels in
+// place if they drifted from the expected ones. The reconciled state is written back into reconciled.
+func ReconcileSecret(client k8s.Client, expected corev1.Secret, owner metav1.Object, reconciled *corev1.Secret) error {
+	key := types.NamespacedName{Namespace: expected.Namespace, Name: expected.Name}
+	return ReconcileResource(client, key, &expected, reconciled, secretManagedFields)
+}
@@ -0,0 +1,88 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package watches provides helpers to dynamically register and unregister watches on resources
+// that are only known at reconcile time (e.g. user-referenced Secrets), on top of the static watches
+// set up once in a controller's SetupWithManager.
+package watches
+
+import "sync"
+
+// DynamicWatches regroups the dynamic watches of a controller, indexed by the kind of resource watched.
+type DynamicWatches struct {
+	Secrets ManagedWatches
+	Pods    ManagedWatches
+}
+
+// NewDynamicWatches creates an initialized DynamicWatches.
+func NewDynamicWatches() DynamicWatches {
+	return DynamicWatches{
+		Secrets: NewManagedWatches(),
+		Pods:    NewManagedWatches(),
+	}
+}
+
+// HandlerRegistration is a named dynamic watch that can be added to or removed from a ManagedWatches.
+type HandlerRegistration interface {
+	// Key identifies this registration among others, so it can be added or removed idempotently.
+	Key() string
+}
+
+// CacheKind identifies which informer cache backs a watched resource.
+type CacheKind int
+
+const (
+	// TypedCache is the default: the full object is kept in the informer cache and available to Get/List.
+	// Use it for resources whose payload the reconciler actually reads out of the cache.
+	TypedCache CacheKind = iota
+	// MetadataCache caches only the PartialObjectMetadata of the watched resource (labels, annotations,
+	// owner references, resource version), cutting memory usage for resources this controller only needs
+	// to know "something changed" about, such as a Pod watched solely to trigger a requeue.
+	MetadataCache
+)
+
+// ManagedWatches tracks the currently active dynamic watches for a single watched Kind, keyed by name.
+type ManagedWatches struct {
+	mutex         sync.RWMutex
+	registrations map[string]HandlerRegistration
+}
+
+// NewManagedWatches creates an initialized ManagedWatches.
+func NewManagedWatches() ManagedWatches {
+	return ManagedWatches{registrations: map[string]HandlerRegistration{}}
+}
+
+// AddHandler registers (or replaces) the given handler, keyed by its Key().
+func (w *ManagedWatches) AddHandler(h HandlerRegistration) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.registrations[h.Key()] = h
+	return nil
+}
+
+// RemoveHandlerForKey removes the registration with the given key, if any.
+func (w *ManagedWatches) RemoveHandlerForKey(key string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	delete(w.registrations, key)
+}
+
+// Registrations returns the names of all currently registered watches.
+func (w *ManagedWatches) Registrations() []string {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	keys := make([]string, 0, len(w.registrations))
+	for k := range w.registrations {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Get returns the registration for the given key, if it exists.
+func (w *ManagedWatches) Get(key string) (HandlerRegistration, bool) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	h, ok := w.registrations[key]
+	return h, ok
+}
@@ -0,0 +1,114 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package watches
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NamedWatch watches a fixed set of resources (identified by name), and maps any event on them
+// to a reconcile.Request for a single owner.
+type NamedWatch struct {
+	// Name identifies this watch among others registered for the same owner.
+	Name string
+	// Watched is the set of namespaced names of the resources to watch.
+	Watched []types.NamespacedName
+	// Watcher is the resource to reconcile whenever one of the Watched resources changes.
+	Watcher types.NamespacedName
+	// Cache is the informer cache this watch is expected to be served from. It does not change how
+	// EventHandler matches events - that only needs the object's metadata either way - but
+	// ReconcileRequestsForCache only dispatches events to registrations whose Cache matches the cache
+	// (metadata-only or typed) its source.Kind watch is backed by.
+	Cache CacheKind
+}
+
+// Key implements HandlerRegistration.
+func (w NamedWatch) Key() string {
+	return w.Name
+}
+
+// EventHandler returns the controller-runtime mapping function to enqueue a reconcile request for the
+// Watcher whenever one of the Watched resources is the subject of an event.
+func (w NamedWatch) EventHandler() handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		for _, watched := range w.Watched {
+			if watched.Namespace == obj.Meta.GetNamespace() && watched.Name == obj.Meta.GetName() {
+				return []reconcile.Request{{NamespacedName: w.Watcher}}
+			}
+		}
+		return nil
+	}
+}
+
+// ReconcileRequests is a handler.ToRequestsFunc-compatible mapping function that dispatches an event to
+// every currently registered NamedWatch, returning a reconcile.Request for each one whose Watched set
+// contains the object the event fired on. It is meant to be wired once, at controller setup time, as the
+// MapFunc for a generic watch over the Kind the ManagedWatches tracks (e.g. Secrets).
+func (w *ManagedWatches) ReconcileRequests(obj handler.MapObject) []reconcile.Request {
+	return w.reconcileRequests(obj, nil)
+}
+
+// ReconcileRequestsForCache returns a handler.ToRequestsFunc that only considers NamedWatch registrations
+// whose Cache matches the given CacheKind. Wire it as the MapFunc for the source.Kind watch backed by
+// that same cache, so a registration never fires off a watch its declared Cache isn't actually served
+// from (e.g. a MetadataCache registration must not depend on events only a typed-object watch delivers).
+func (w *ManagedWatches) ReconcileRequestsForCache(cache CacheKind) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		return w.reconcileRequests(obj, &cache)
+	}
+}
+
+func (w *ManagedWatches) reconcileRequests(obj handler.MapObject, cache *CacheKind) []reconcile.Request {
+	w.mutex.RLock()
+	registrations := make([]HandlerRegistration, 0, len(w.registrations))
+	for _, h := range w.registrations {
+		registrations = append(registrations, h)
+	}
+	w.mutex.RUnlock()
+
+	var requests []reconcile.Request
+	for _, h := range registrations {
+		namedWatch, ok := h.(NamedWatch)
+		if !ok {
+			continue
+		}
+		if cache != nil && namedWatch.Cache != *cache {
+			continue
+		}
+		requests = append(requests, namedWatch.EventHandler()(obj)...)
+	}
+	return requests
+}
+
+// WatchUserProvidedSecrets registers (or clears, if secretNames is empty) a dynamic watch named watchName
+// that enqueues a reconcile request for owner whenever one of the given Secrets changes. User-provided
+// Secrets are only ever used to trigger a requeue here - the reconciler re-Gets the ones it needs - so
+// they are backed by a MetadataCache.
+func WatchUserProvidedSecrets(owner types.NamespacedName, dynamicWatches DynamicWatches, watchName string, secretNames []string) error {
+	return WatchSecrets(owner, dynamicWatches, watchName, secretNames, MetadataCache)
+}
+
+// WatchSecrets is the general form of WatchUserProvidedSecrets: it lets the caller pick which cache the
+// watched Secrets should be served from, as a per-source flag on this dynamic-watch registration. Pick
+// MetadataCache for Secrets this controller only watches to trigger a requeue, or TypedCache for Secrets
+// the reconciler reads out of the informer cache with their Data already populated.
+func WatchSecrets(owner types.NamespacedName, dynamicWatches DynamicWatches, watchName string, secretNames []string, cache CacheKind) error {
+	if len(secretNames) == 0 {
+		dynamicWatches.Secrets.RemoveHandlerForKey(watchName)
+		return nil
+	}
+	watched := make([]types.NamespacedName, 0, len(secretNames))
+	for _, name := range secretNames {
+		watched = append(watched, types.NamespacedName{Namespace: owner.Namespace, Name: name})
+	}
+	return dynamicWatches.Secrets.AddHandler(NamedWatch{
+		Name:    watchName,
+		Watched: watched,
+		Watcher: owner,
+		Cache:   cache,
+	})
+}
@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package watches
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileRequestsForCache_OnlyMatchesItsOwnCacheKind(t *testing.T) {
+	managedWatches := NewManagedWatches()
+	owner := types.NamespacedName{Namespace: "ns", Name: "owner"}
+	watched := types.NamespacedName{Namespace: "ns", Name: "secret"}
+
+	require.NoError(t, managedWatches.AddHandler(NamedWatch{
+		Name: "metadata-watch", Watched: []types.NamespacedName{watched}, Watcher: owner, Cache: MetadataCache,
+	}))
+	require.NoError(t, managedWatches.AddHandler(NamedWatch{
+		Name: "typed-watch", Watched: []types.NamespacedName{watched}, Watcher: owner, Cache: TypedCache,
+	}))
+
+	event := handler.MapObject{Meta: &metav1.ObjectMeta{Namespace: "ns", Name: "secret"}}
+	want := []reconcile.Request{{NamespacedName: owner}}
+
+	// a MetadataCache-backed watch only fires the registration that declared MetadataCache ...
+	require.Equal(t, want, managedWatches.ReconcileRequestsForCache(MetadataCache)(event))
+	// ... and a TypedCache-backed watch only fires the one that declared TypedCache.
+	require.Equal(t, want, managedWatches.ReconcileRequestsForCache(TypedCache)(event))
+
+	// ReconcileRequests (no cache filter) still fires both, once each.
+	require.ElementsMatch(t, []reconcile.Request{{NamespacedName: owner}, {NamespacedName: owner}}, managedWatches.ReconcileRequests(event))
+}
+
+func TestReconcileRequestsForCache_NoMatchForOtherCacheKind(t *testing.T) {
+	managedWatches := NewManagedWatches()
+	owner := types.NamespacedName{Namespace: "ns", Name: "owner"}
+	watched := types.NamespacedName{Namespace: "ns", Name: "secret"}
+
+	require.NoError(t, managedWatches.AddHandler(NamedWatch{
+		Name: "metadata-watch", Watched: []types.NamespacedName{watched}, Watcher: owner, Cache: MetadataCache,
+	}))
+
+	event := handler.MapObject{Meta: &metav1.ObjectMeta{Namespace: "ns", Name: "secret"}}
+	require.Empty(t, managedWatches.ReconcileRequestsForCache(TypedCache)(event))
+}
@@ -0,0 +1,190 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package remotecluster
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+const (
+	// ManagedRemoteClustersAnnotationName used to hold the comma-separated list of remote clusters that
+	// had been created. It is no longer written, but is still read once by the migration shim below: a
+	// single comma-separated value does not scale past a few hundred remote clusters before running into
+	// etcd/annotation size limits, and a single annotation update races with anything else patching the
+	// Elasticsearch resource.
+	//
+	// Deprecated: superseded by the <es-name>-es-remote-clusters-state Secret.
+	ManagedRemoteClustersAnnotationName = "elasticsearch.k8s.elastic.co/managed-remote-clusters"
+
+	stateSecretSuffix = "-es-remote-clusters-state"
+	stateSecretKey    = "remote-clusters.json"
+)
+
+// stateSecretName returns the name of the Secret holding the managed remote clusters state for the
+// Elasticsearch resource named esName.
+func stateSecretName(esName string) string {
+	return esName + stateSecretSuffix
+}
+
+// RemoteClusterEntry is the state tracked for a single managed remote cluster.
+type RemoteClusterEntry struct {
+	Name      string      `json:"name"`
+	SeedHosts []string    `json:"seedHosts,omitempty"`
+	CreatedAt metav1.Time `json:"createdAt"`
+}
+
+// RemoteClusterState is the JSON payload stored in the remote clusters state Secret.
+type RemoteClusterState struct {
+	Clusters []RemoteClusterEntry `json:"clusters"`
+}
+
+// getRemoteClustersInState returns a set that contains the list of remote clusters that have already been
+// created for this Elasticsearch resource. A map is returned here to quickly compare with the ones that
+// are new or missing. If there are no remote clusters the map is empty but not nil.
+func getRemoteClustersInState(c k8s.Client, es esv1.Elasticsearch) (map[string]struct{}, error) {
+	state, _, err := getOrMigrateRemoteClustersState(c, es)
+	if err != nil {
+		return nil, err
+	}
+	remoteClusters := make(map[string]struct{}, len(state.Clusters))
+	for _, cluster := range state.Clusters {
+		remoteClusters[cluster.Name] = struct{}{}
+	}
+	return remoteClusters, nil
+}
+
+// updateRemoteClustersState persists remoteClusters as the new set of managed remote clusters for es,
+// preserving the CreatedAt/SeedHosts already recorded for clusters that are still present. It relies on
+// the state Secret's resourceVersion, fetched moments earlier by getOrMigrateRemoteClustersState, so a
+// concurrent writer loses the race with a Conflict error rather than silently clobbering the other update.
+func updateRemoteClustersState(c k8s.Client, es esv1.Elasticsearch, remoteClusters map[string]struct{}) error {
+	currentState, secret, err := getOrMigrateRemoteClustersState(c, es)
+	if err != nil {
+		return err
+	}
+
+	existingByName := make(map[string]RemoteClusterEntry, len(currentState.Clusters))
+	for _, entry := range currentState.Clusters {
+		existingByName[entry.Name] = entry
+	}
+
+	newState := RemoteClusterState{Clusters: make([]RemoteClusterEntry, 0, len(remoteClusters))}
+	for name := range remoteClusters {
+		if entry, ok := existingByName[name]; ok {
+			newState.Clusters = append(newState.Clusters, entry)
+			continue
+		}
+		newState.Clusters = append(newState.Clusters, RemoteClusterEntry{Name: name, CreatedAt: metav1.Now()})
+	}
+	sort.Slice(newState.Clusters, func(i, j int) bool { return newState.Clusters[i].Name < newState.Clusters[j].Name })
+
+	return saveRemoteClustersState(c, secret, newState)
+}
+
+// getOrMigrateRemoteClustersState returns the current RemoteClusterState for es, along with the backing
+// Secret (its resourceVersion populated if it already existed). If the state Secret does not exist yet but
+// the legacy annotation does, the annotation is migrated into a freshly created Secret and then cleared
+// from the Elasticsearch resource.
+func getOrMigrateRemoteClustersState(c k8s.Client, es esv1.Elasticsearch) (RemoteClusterState, *corev1.Secret, error) {
+	key := types.NamespacedName{Namespace: es.Namespace, Name: stateSecretName(es.Name)}
+	var secret corev1.Secret
+	err := c.Get(key, &secret)
+	if err != nil && apierrors.IsNotFound(err) {
+		migrated := migrateRemoteClustersAnnotation(es)
+		secret = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       es.Namespace,
+				Name:            stateSecretName(es.Name),
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&es, esv1.GroupVersion.WithKind("Elasticsearch"))},
+			},
+		}
+		if err := writeRemoteClustersState(&secret, migrated); err != nil {
+			return RemoteClusterState{}, nil, err
+		}
+		if err := c.Create(&secret); err != nil {
+			return RemoteClusterState{}, nil, err
+		}
+		if err := clearRemoteClustersAnnotation(c, es); err != nil {
+			return RemoteClusterState{}, nil, err
+		}
+		return migrated, &secret, nil
+	} else if err != nil {
+		return RemoteClusterState{}, nil, err
+	} else if err := clearRemoteClustersAnnotation(c, es); err != nil {
+		// the Secret already exists: either migration already completed, or a previous attempt created
+		// the Secret but crashed before clearing the annotation. Retry clearing it here so a stuck
+		// annotation can't outlive a partial migration.
+		return RemoteClusterState{}, nil, err
+	}
+
+	var state RemoteClusterState
+	if len(secret.Data[stateSecretKey]) > 0 {
+		if err := json.Unmarshal(secret.Data[stateSecretKey], &state); err != nil {
+			return RemoteClusterState{}, nil, err
+		}
+	}
+	return state, &secret, nil
+}
+
+// migrateRemoteClustersAnnotation reads the legacy comma-separated annotation, if present, and converts it
+// to a RemoteClusterState. It returns an empty state if the annotation is absent.
+func migrateRemoteClustersAnnotation(es esv1.Elasticsearch) RemoteClusterState {
+	serialized, ok := es.Annotations[ManagedRemoteClustersAnnotationName]
+	if !ok || len(serialized) == 0 {
+		return RemoteClusterState{}
+	}
+	names := strings.Split(serialized, ",")
+	state := RemoteClusterState{Clusters: make([]RemoteClusterEntry, 0, len(names))}
+	now := metav1.Now()
+	for _, name := range names {
+		state.Clusters = append(state.Clusters, RemoteClusterEntry{Name: name, CreatedAt: now})
+	}
+	sort.Slice(state.Clusters, func(i, j int) bool { return state.Clusters[i].Name < state.Clusters[j].Name })
+	return state
+}
+
+// clearRemoteClustersAnnotation removes the legacy annotation from es, if present.
+func clearRemoteClustersAnnotation(c k8s.Client, es esv1.Elasticsearch) error {
+	if _, ok := es.Annotations[ManagedRemoteClustersAnnotationName]; !ok {
+		return nil
+	}
+	delete(es.Annotations, ManagedRemoteClustersAnnotationName)
+	return c.Update(&es)
+}
+
+// saveRemoteClustersState writes state into secret and creates or updates it, depending on whether it was
+// already persisted.
+func saveRemoteClustersState(c k8s.Client, secret *corev1.Secret, state RemoteClusterState) error {
+	if err := writeRemoteClustersState(secret, state); err != nil {
+		return err
+	}
+	if secret.ResourceVersion == "" {
+		return c.Create(secret)
+	}
+	return c.Update(secret)
+}
+
+// writeRemoteClustersState marshals state into secret's data.
+func writeRemoteClustersState(secret *corev1.Secret, state RemoteClusterState) error {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[stateSecretKey] = payload
+	return nil
+}
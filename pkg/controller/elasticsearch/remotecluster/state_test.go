@@ -0,0 +1,115 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package remotecluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+func TestGetOrMigrateRemoteClustersState_MigratesAnnotation(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "es",
+			Annotations: map[string]string{ManagedRemoteClustersAnnotationName: "cluster-a,cluster-b"},
+		},
+	}
+	client := k8s.WrappedFakeClient(&es)
+
+	remoteClusters, err := getRemoteClustersInState(client, es)
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"cluster-a": {}, "cluster-b": {}}, remoteClusters)
+
+	// the state Secret was created, owned by the Elasticsearch resource
+	var secret corev1.Secret
+	require.NoError(t, client.Get(types.NamespacedName{Namespace: "ns", Name: stateSecretName("es")}, &secret))
+	require.Len(t, secret.OwnerReferences, 1)
+	require.Equal(t, "es", secret.OwnerReferences[0].Name)
+	require.Equal(t, "Elasticsearch", secret.OwnerReferences[0].Kind)
+
+	// and the legacy annotation was cleared from the Elasticsearch resource
+	var updated esv1.Elasticsearch
+	require.NoError(t, client.Get(types.NamespacedName{Namespace: "ns", Name: "es"}, &updated))
+	_, stillPresent := updated.Annotations[ManagedRemoteClustersAnnotationName]
+	require.False(t, stillPresent)
+}
+
+func TestGetOrMigrateRemoteClustersState_ResumesInterruptedMigration(t *testing.T) {
+	// simulates a crash between the Secret being created and the legacy annotation being cleared: the
+	// state Secret already holds the migrated data, but the annotation is still present on the resource.
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "es",
+			Annotations: map[string]string{ManagedRemoteClustersAnnotationName: "cluster-a"},
+		},
+	}
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: stateSecretName("es")},
+		Data:       map[string][]byte{stateSecretKey: []byte(`{"clusters":[{"name":"cluster-a","createdAt":"2020-01-01T00:00:00Z"}]}`)},
+	}
+	client := k8s.WrappedFakeClient(&es, &secret)
+
+	remoteClusters, err := getRemoteClustersInState(client, es)
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"cluster-a": {}}, remoteClusters)
+
+	var updated esv1.Elasticsearch
+	require.NoError(t, client.Get(types.NamespacedName{Namespace: "ns", Name: "es"}, &updated))
+	_, stillPresent := updated.Annotations[ManagedRemoteClustersAnnotationName]
+	require.False(t, stillPresent, "the stale annotation should be cleared on the next call, even though the Secret already existed")
+}
+
+func TestUpdateRemoteClustersState_LargeSet(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	client := k8s.WrappedFakeClient(&es)
+
+	const clusterCount = 1500
+	remoteClusters := make(map[string]struct{}, clusterCount)
+	for i := 0; i < clusterCount; i++ {
+		remoteClusters[fmt.Sprintf("cluster-%d", i)] = struct{}{}
+	}
+
+	require.NoError(t, updateRemoteClustersState(client, es, remoteClusters))
+
+	got, err := getRemoteClustersInState(client, es)
+	require.NoError(t, err)
+	require.Len(t, got, clusterCount)
+	for name := range remoteClusters {
+		_, ok := got[name]
+		require.True(t, ok, "expected %s to be present in the state", name)
+	}
+}
+
+func TestUpdateRemoteClustersState_PreservesCreatedAt(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	client := k8s.WrappedFakeClient(&es)
+
+	require.NoError(t, updateRemoteClustersState(client, es, map[string]struct{}{"cluster-a": {}}))
+	state, _, err := getOrMigrateRemoteClustersState(client, es)
+	require.NoError(t, err)
+	require.Len(t, state.Clusters, 1)
+	createdAt := state.Clusters[0].CreatedAt
+
+	// adding cluster-b should not bump CreatedAt for the already-known cluster-a
+	require.NoError(t, updateRemoteClustersState(client, es, map[string]struct{}{"cluster-a": {}, "cluster-b": {}}))
+	state, _, err = getOrMigrateRemoteClustersState(client, es)
+	require.NoError(t, err)
+	require.Len(t, state.Clusters, 2)
+	for _, entry := range state.Clusters {
+		if entry.Name == "cluster-a" {
+			require.Equal(t, createdAt, entry.CreatedAt)
+		}
+	}
+}
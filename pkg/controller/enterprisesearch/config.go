@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package enterprisesearch
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	entv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	entName "github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+const (
+	// ConfigFilename is the name of the Enterprise Search configuration file mounted into the Pods.
+	ConfigFilename = "enterprise-search.yml"
+)
+
+// reconcileConfig reconciles the Secret holding the Enterprise Search configuration file derived from the spec.
+func reconcileConfig(client k8s.Client, ent entv1beta1.EnterpriseSearch) (corev1.Secret, error) {
+	cfgBytes, err := buildConfig(ent)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ent.Namespace,
+			Name:      entName.ConfigSecretName(ent.Name),
+			Labels:    NewLabels(k8s.ExtractNamespacedName(&ent)),
+		},
+		Data: map[string][]byte{
+			ConfigFilename: cfgBytes,
+		},
+	}
+	var reconciled corev1.Secret
+	if err := reconciler.ReconcileSecret(client, expected, &ent, &reconciled); err != nil {
+		return corev1.Secret{}, err
+	}
+	return reconciled, nil
+}
+
+// buildConfig renders the enterprise-search.yml configuration file from the Enterprise Search spec.
+func buildConfig(ent entv1beta1.EnterpriseSearch) ([]byte, error) {
+	externalURL := fmt.Sprintf("https://%s.%s.svc:3002", entName.HTTPService(ent.Name), ent.Namespace)
+	cfg := fmt.Sprintf("external_url: %s\nent_search.listen_port: 3002\n", externalURL)
+	return []byte(cfg), nil
+}
@@ -0,0 +1,47 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package enterprisesearch
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	entv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	entName "github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// deploymentManagedFields are the only subtrees of the Deployment this operator owns. Everything else -
+// status, a defaulted spec.strategy, an annotation added by a mutating webhook - is preserved from the
+// live object.
+var deploymentManagedFields = reconciler.ManagedFields{"$.spec.replicas", "$.spec.template"}
+
+// reconcileDeployment creates or updates the Enterprise Search Deployment, reverting any drift on the
+// fields this operator owns (replica count, Pod template) while leaving everything else untouched.
+func reconcileDeployment(client k8s.Client, ent entv1beta1.EnterpriseSearch, podTemplate corev1.PodTemplateSpec) (appsv1.Deployment, error) {
+	replicas := ent.Spec.Count
+	expected := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ent.Namespace,
+			Name:      entName.EntDeploymentName(ent.Name),
+			Labels:    NewLabels(k8s.ExtractNamespacedName(&ent)),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: NewLabels(k8s.ExtractNamespacedName(&ent))},
+			Template: podTemplate,
+		},
+	}
+
+	var reconciled appsv1.Deployment
+	key := types.NamespacedName{Namespace: expected.Namespace, Name: expected.Name}
+	if err := reconciler.ReconcileResource(client, key, &expected, &reconciled, deploymentManagedFields); err != nil {
+		return appsv1.Deployment{}, err
+	}
+	return reconciled, nil
+}
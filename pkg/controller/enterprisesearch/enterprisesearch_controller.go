@@ -0,0 +1,255 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package enterprisesearch reconciles Enterprise Search resources: it deploys Enterprise Search as a
+// Deployment, exposes it through a Service, manages its HTTP certificates and configuration, and keeps
+// all of it in sync with the watched Elasticsearch backend.
+package enterprisesearch
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	entv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+const (
+	name = "enterprisesearch-controller"
+)
+
+// Add creates a new EnterpriseSearch Controller and adds it to the Manager.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	return addWatches(mgr, r)
+}
+
+func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileEnterpriseSearch {
+	return &ReconcileEnterpriseSearch{
+		Client:         k8s.WrapClient(mgr.GetClient()),
+		recorder:       mgr.GetEventRecorderFor(name),
+		dynamicWatches: watches.NewDynamicWatches(),
+		Parameters:     params,
+	}
+}
+
+func addWatches(mgr manager.Manager, r *ReconcileEnterpriseSearch) error {
+	c, err := ctrl.New(name, mgr, ctrl.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &entv1beta1.EnterpriseSearch{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	if err := c.Watch(
+		&source.Kind{Type: &appsv1.Deployment{}},
+		&handler.EnqueueRequestForOwner{OwnerType: &entv1beta1.EnterpriseSearch{}, IsController: true},
+	); err != nil {
+		return err
+	}
+	// podsToReconcilerequest only reads the EnterpriseSearchNameLabelName label, never the Pod's payload,
+	// so this watch is on PartialObjectMetadata and served from the manager's metadata-only cache rather
+	// than pulling full Pod objects - containers, volumes, status - into memory.
+	podMeta := &metav1.PartialObjectMetadata{}
+	podMeta.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+	if err := c.Watch(
+		&source.Kind{Type: podMeta},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(podsToReconcilerequest)},
+	); err != nil {
+		return err
+	}
+	// Any event on a Secret that is dynamically watched - a user-provided configRef Secret, or a
+	// user-provided HTTP certificate Secret - is mapped back to the EnterpriseSearch that registered the
+	// watch, so a rotation or in-place edit triggers a deterministic rolling restart. Each dynamic watch
+	// declares, through its CacheKind, which of the two watches below actually delivers its events: a
+	// MetadataCache registration (the only kind WatchUserProvidedSecrets creates today, since it only
+	// matches on namespace/name) is served from the metadata-only watch; a future TypedCache registration,
+	// for a consumer that needs the Secret's Data already populated in the informer cache, would be served
+	// from the typed one instead.
+	secretMeta := &metav1.PartialObjectMetadata{}
+	secretMeta.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	if err := c.Watch(
+		&source.Kind{Type: secretMeta},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: r.dynamicWatches.Secrets.ReconcileRequestsForCache(watches.MetadataCache),
+		},
+	); err != nil {
+		return err
+	}
+	if err := c.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: r.dynamicWatches.Secrets.ReconcileRequestsForCache(watches.TypedCache),
+		},
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileEnterpriseSearch{}
+
+// ReconcileEnterpriseSearch reconciles an EnterpriseSearch object.
+type ReconcileEnterpriseSearch struct {
+	k8s.Client
+	recorder       record.EventRecorder
+	dynamicWatches watches.DynamicWatches
+	Parameters     operator.Parameters
+}
+
+// DynamicWatches returns the dynamic watches registered by this controller.
+func (r *ReconcileEnterpriseSearch) DynamicWatches() watches.DynamicWatches {
+	return r.dynamicWatches
+}
+
+// Reconcile reads the state of the cluster for an EnterpriseSearch object and makes changes based on the
+// state read and what is in the EnterpriseSearch.Spec.
+func (r *ReconcileEnterpriseSearch) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	var ent entv1beta1.EnterpriseSearch
+	err := r.Client.Get(request.NamespacedName, &ent)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.onDelete(request.NamespacedName)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if common.IsUnmanaged(&ent) {
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.reconcileControllerVersion(&ent); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := validate(ent); err != nil {
+		r.recorder.Event(&ent, corev1.EventTypeWarning, "ValidationError", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	if ent.Spec.ElasticsearchRef.IsDefined() && !isAssociationConfigured(ent) {
+		r.recorder.Event(&ent, corev1.EventTypeWarning, "AssociationError", "Elasticsearch backend is not configured")
+		return reconcile.Result{}, nil
+	}
+
+	return r.doReconcile(ent)
+}
+
+func (r *ReconcileEnterpriseSearch) reconcileControllerVersion(ent *entv1beta1.EnterpriseSearch) error {
+	if ent.Annotations[annotation.ControllerVersionAnnotation] == r.Parameters.OperatorInfo.BuildInfo.Version {
+		return nil
+	}
+	if ent.Annotations == nil {
+		ent.Annotations = map[string]string{}
+	}
+	ent.Annotations[annotation.ControllerVersionAnnotation] = r.Parameters.OperatorInfo.BuildInfo.Version
+	return r.Client.Update(ent)
+}
+
+// isAssociationConfigured returns true once the Elasticsearch association has produced the Secret
+// holding the connection settings Enterprise Search needs to talk to its backend.
+func isAssociationConfigured(ent entv1beta1.EnterpriseSearch) bool {
+	return len(ent.Annotations[associationConfAnnotationName]) > 0
+}
+
+const associationConfAnnotationName = "association.k8s.elastic.co/es-conf"
+
+func (r *ReconcileEnterpriseSearch) doReconcile(ent entv1beta1.EnterpriseSearch) (reconcile.Result, error) {
+	owner := k8s.ExtractNamespacedName(&ent)
+
+	userConfigSecretNames := make([]string, 0, 1)
+	if len(ent.Spec.ConfigRef.SecretName) > 0 {
+		userConfigSecretNames = append(userConfigSecretNames, ent.Spec.ConfigRef.SecretName)
+	}
+	if err := watches.WatchUserProvidedSecrets(owner, r.dynamicWatches, configRefWatchName(owner), userConfigSecretNames); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	internalCerts, publicCerts, userCertSecretName, err := reconcileHTTPCertificates(r.Client, ent)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	userCertSecretNames := make([]string, 0, 1)
+	if len(userCertSecretName) > 0 {
+		userCertSecretNames = append(userCertSecretNames, userCertSecretName)
+	}
+	if err := watches.WatchUserProvidedSecrets(owner, r.dynamicWatches, httpCertificateWatchName(owner), userCertSecretNames); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	config, err := reconcileConfig(r.Client, ent)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := reconcileAuthProxyRBAC(r.Client, ent); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	rolloutInputs := []corev1.Secret{internalCerts, publicCerts}
+	for _, secretName := range userConfigSecretNames {
+		var userSecret corev1.Secret
+		if err := r.Client.Get(types.NamespacedName{Namespace: ent.Namespace, Name: secretName}, &userSecret); err != nil {
+			return reconcile.Result{}, err
+		}
+		rolloutInputs = append(rolloutInputs, userSecret)
+	}
+
+	podTemplate := buildPodTemplate(ent, config, rolloutInputs...)
+
+	if _, err := reconcileService(r.Client, ent); err != nil {
+		return reconcile.Result{}, err
+	}
+	if _, err := reconcileDeployment(r.Client, ent, podTemplate); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{RequeueAfter: certExpiration - certRenewBefore}, nil
+}
+
+// onDelete clears out the dynamic watches this controller may have registered for the deleted resource.
+func (r *ReconcileEnterpriseSearch) onDelete(owner types.NamespacedName) {
+	_ = watches.WatchUserProvidedSecrets(owner, r.dynamicWatches, configRefWatchName(owner), nil)
+	_ = watches.WatchUserProvidedSecrets(owner, r.dynamicWatches, httpCertificateWatchName(owner), nil)
+}
+
+// configRefWatchName returns the name of the dynamic watch tracking the user-provided configRef Secret
+// of the given EnterpriseSearch resource.
+func configRefWatchName(owner types.NamespacedName) string {
+	return fmt.Sprintf("%s-ent-configref", owner.Name)
+}
+
+// httpCertificateWatchName returns the name of the dynamic watch tracking the user-provided HTTP
+// certificate Secret of the given EnterpriseSearch resource.
+func httpCertificateWatchName(owner types.NamespacedName) string {
+	return fmt.Sprintf("%s-ent-http-certificate", owner.Name)
+}
+
+// podsToReconcilerequest maps a Pod event to a reconcile.Request for the EnterpriseSearch resource that
+// owns it, as identified by the EnterpriseSearchNameLabelName label.
+func podsToReconcilerequest(object handler.MapObject) []reconcile.Request {
+	entName, ok := object.Meta.GetLabels()[EnterpriseSearchNameLabelName]
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: object.Meta.GetNamespace(), Name: entName}},
+	}
+}
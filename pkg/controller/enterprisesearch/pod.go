@@ -0,0 +1,104 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package enterprisesearch
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	entv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/authproxy"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	entName "github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+const (
+	// ConfigHashLabelName is set on the Pod template and bumped whenever the generated Enterprise Search
+	// configuration changes, to force a rolling restart of the Pods.
+	ConfigHashLabelName = "enterprisesearch.k8s.elastic.co/config-hash"
+
+	// configChecksumAnnotationName is set on the Pod template and bumped whenever any of the rollout inputs
+	// changes: the generated configuration, any user-provided configRef Secret, and the internal or public
+	// HTTP certificates. This triggers a rolling restart whenever one of those Secrets is rotated or edited
+	// in place, not just when the operator itself regenerates the config. The auth-proxy serving
+	// certificate Secret is not part of the checksum.
+	configChecksumAnnotationName = "checksum/config"
+
+	configVolumeName = "config"
+	configMountPath  = "/usr/share/enterprise-search/config"
+
+	httpCertsVolumeName = "http-certs"
+	httpCertsMountPath  = "/usr/share/enterprise-search/config/http-certs"
+)
+
+// buildPodTemplate builds the Pod template for the Enterprise Search Deployment, stamping a rollout
+// checksum derived from configSecret and every Secret in rolloutInputs so that the Deployment controller
+// rolls the Pods whenever one of those Secrets changes, even when the change did not originate from this
+// reconciler.
+func buildPodTemplate(ent entv1beta1.EnterpriseSearch, configSecret corev1.Secret, rolloutInputs ...corev1.Secret) corev1.PodTemplateSpec {
+	labels := NewLabels(k8s.ExtractNamespacedName(&ent))
+	labels[ConfigHashLabelName] = hash.HashObject(configSecret.Data)
+
+	checksumInputs := make([]map[string][]byte, 0, len(rolloutInputs)+1)
+	checksumInputs = append(checksumInputs, configSecret.Data)
+	for _, s := range rolloutInputs {
+		checksumInputs = append(checksumInputs, s.Data)
+	}
+
+	containers := []corev1.Container{
+		{
+			Name:  entName.EntDeploymentName(ent.Name),
+			Image: containerImage(ent.Spec.Version),
+			Ports: []corev1.ContainerPort{
+				{Name: "http", ContainerPort: HTTPPort, Protocol: corev1.ProtocolTCP},
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: configVolumeName, MountPath: configMountPath, ReadOnly: true},
+				{Name: httpCertsVolumeName, MountPath: httpCertsMountPath, ReadOnly: true},
+			},
+		},
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: configVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: configSecret.Name},
+			},
+		},
+		{
+			Name: httpCertsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: entName.HTTPCertsInternalSecretName(ent.Name)},
+			},
+		},
+	}
+
+	var serviceAccountName string
+	if ent.Spec.Metrics.AuthProxy.Enabled {
+		cfg := authProxyConfig(ent)
+		containers = append(containers, authproxy.Container(cfg))
+		volumes = append(volumes, authproxy.Volume(cfg))
+		serviceAccountName = cfg.ServiceAccountName()
+	}
+
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: labels,
+			Annotations: map[string]string{
+				configChecksumAnnotationName: hash.HashObject(checksumInputs),
+			},
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: serviceAccountName,
+			Containers:         containers,
+			Volumes:            volumes,
+		},
+	}
+}
+
+func containerImage(version string) string {
+	return "docker.elastic.co/enterprise-search/enterprise-search:" + version
+}
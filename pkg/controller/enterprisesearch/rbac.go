@@ -0,0 +1,148 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package enterprisesearch
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	entv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/authproxy"
+	entName "github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// AuthProxyPort is the port the oauth-proxy sidecar listens on when spec.metrics.authProxy is enabled.
+const AuthProxyPort = 4443
+
+// authProxyConfig builds the authproxy.Config for the given EnterpriseSearch resource. The proxy
+// terminates TLS with its own serving certificate Secret, minted out-of-band by OpenShift's
+// service-serving-cert controller (via the annotation reconcileService attaches to the Service). On
+// non-OpenShift clusters the operator does not create that Secret.
+func authProxyConfig(ent entv1beta1.EnterpriseSearch) authproxy.Config {
+	resource := ent.Spec.Metrics.AuthProxy.Resource
+	if resource == "" {
+		resource = ent.Name
+	}
+	return authproxy.Config{
+		Namespace:             ent.Namespace,
+		Name:                  ent.Name,
+		Labels:                NewLabels(k8s.ExtractNamespacedName(&ent)),
+		ServingCertSecretName: entName.AuthProxyServingCertSecretName(ent.Name),
+		UpstreamPort:          HTTPPort,
+		ProxyPort:             AuthProxyPort,
+		ResourceAttribute: authproxy.ResourceAttribute{
+			APIGroup: entv1beta1.GroupVersion.Group,
+			Resource: "enterprisesearches",
+			Name:     resource,
+		},
+	}
+}
+
+// reconcileAuthProxyRBAC creates or updates the ServiceAccount, ClusterRole and ClusterRoleBinding
+// backing the oauth-proxy sidecar when spec.metrics.authProxy is enabled, granting it just enough
+// privilege (`create` on tokenreviews and subjectaccessreviews) to delegate AuthN/AuthZ to the API
+// server. When the toggle is off, any such objects left over from a previous reconcile are removed.
+func reconcileAuthProxyRBAC(client k8s.Client, ent entv1beta1.EnterpriseSearch) error {
+	cfg := authProxyConfig(ent)
+
+	if !ent.Spec.Metrics.AuthProxy.Enabled {
+		return deleteAuthProxyRBAC(client, cfg)
+	}
+
+	if err := reconcileAuthProxyServiceAccount(client, cfg); err != nil {
+		return err
+	}
+	if err := reconcileAuthProxyClusterRole(client, cfg); err != nil {
+		return err
+	}
+	return reconcileAuthProxyClusterRoleBinding(client, cfg)
+}
+
+func reconcileAuthProxyServiceAccount(client k8s.Client, cfg authproxy.Config) error {
+	expected := authproxy.ServiceAccount(cfg)
+	var reconciled corev1.ServiceAccount
+	key := types.NamespacedName{Namespace: expected.Namespace, Name: expected.Name}
+	err := client.Get(key, &reconciled)
+	if err != nil && apierrors.IsNotFound(err) {
+		return client.Create(&expected)
+	} else if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(reconciled.Labels, expected.Labels) {
+		reconciled.Labels = expected.Labels
+		return client.Update(&reconciled)
+	}
+	return nil
+}
+
+func reconcileAuthProxyClusterRole(client k8s.Client, cfg authproxy.Config) error {
+	expected := authproxy.ClusterRole(cfg)
+	var reconciled rbacv1.ClusterRole
+	key := types.NamespacedName{Name: expected.Name}
+	err := client.Get(key, &reconciled)
+	if err != nil && apierrors.IsNotFound(err) {
+		return client.Create(&expected)
+	} else if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(reconciled.Rules, expected.Rules) {
+		reconciled.Rules = expected.Rules
+		return client.Update(&reconciled)
+	}
+	return nil
+}
+
+func reconcileAuthProxyClusterRoleBinding(client k8s.Client, cfg authproxy.Config) error {
+	expected := authproxy.ClusterRoleBinding(cfg)
+	var reconciled rbacv1.ClusterRoleBinding
+	key := types.NamespacedName{Name: expected.Name}
+	err := client.Get(key, &reconciled)
+	if err != nil && apierrors.IsNotFound(err) {
+		return client.Create(&expected)
+	} else if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(reconciled.RoleRef, expected.RoleRef) || !reflect.DeepEqual(reconciled.Subjects, expected.Subjects) {
+		reconciled.RoleRef = expected.RoleRef
+		reconciled.Subjects = expected.Subjects
+		return client.Update(&reconciled)
+	}
+	return nil
+}
+
+// deleteAuthProxyRBAC removes any ServiceAccount, ClusterRole and ClusterRoleBinding previously created
+// for the oauth-proxy sidecar, so toggling spec.metrics.authProxy off leaves nothing behind.
+func deleteAuthProxyRBAC(client k8s.Client, cfg authproxy.Config) error {
+	sa := authproxy.ServiceAccount(cfg)
+	if err := deleteIfFound(client, &corev1.ServiceAccount{}, types.NamespacedName{Namespace: sa.Namespace, Name: sa.Name}); err != nil {
+		return err
+	}
+	role := authproxy.ClusterRole(cfg)
+	if err := deleteIfFound(client, &rbacv1.ClusterRole{}, types.NamespacedName{Name: role.Name}); err != nil {
+		return err
+	}
+	binding := authproxy.ClusterRoleBinding(cfg)
+	return deleteIfFound(client, &rbacv1.ClusterRoleBinding{}, types.NamespacedName{Name: binding.Name})
+}
+
+// deleteIfFound deletes obj if it exists, tolerating it being already gone.
+func deleteIfFound(client k8s.Client, obj runtime.Object, key types.NamespacedName) error {
+	if err := client.Get(key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := client.Delete(obj); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package enterprisesearch
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	entv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/authproxy"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	entName "github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// HTTPPort is the port Enterprise Search listens on for HTTP traffic.
+const HTTPPort = 3002
+
+// servingCertAnnotationPath is the only annotation key this operator owns on the Service - scoping to the
+// single key, rather than the whole $.metadata.annotations map, lets other annotations (added by webhooks,
+// ingress controllers, etc.) survive reconciliation untouched.
+var servingCertAnnotationPath = "$.metadata.annotations['" + authproxy.OpenShiftServingCertAnnotation + "']"
+
+// serviceManagedFields are the only subtrees of the Service this operator owns. Everything else - the
+// ClusterIP assigned by Kubernetes, the Type defaulted by the API server - is preserved from the live
+// object.
+var serviceManagedFields = reconciler.ManagedFields{"$.spec.selector", "$.spec.ports", servingCertAnnotationPath}
+
+// reconcileService creates or updates the Enterprise Search HTTP Service, preserving any field not
+// owned by this operator (e.g. the ClusterIP assigned by Kubernetes).
+func reconcileService(client k8s.Client, ent entv1beta1.EnterpriseSearch) (corev1.Service, error) {
+	// When the oauth-proxy sidecar is enabled, it is the one terminating TLS and enforcing AuthN/AuthZ: the
+	// Service must target it rather than the Enterprise Search container directly.
+	targetPort := HTTPPort
+	var annotations map[string]string
+	if ent.Spec.Metrics.AuthProxy.Enabled {
+		targetPort = AuthProxyPort
+		// Ask OpenShift's service-serving-cert controller to mint and maintain the proxy's serving
+		// certificate in ServingCertSecretName. On non-OpenShift clusters this annotation is ignored and
+		// the operator does not create that Secret.
+		cfg := authProxyConfig(ent)
+		annotations = map[string]string{authproxy.OpenShiftServingCertAnnotation: cfg.ServingCertSecretName}
+	}
+
+	expected := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   ent.Namespace,
+			Name:        entName.HTTPService(ent.Name),
+			Labels:      NewLabels(k8s.ExtractNamespacedName(&ent)),
+			Annotations: annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: NewLabels(k8s.ExtractNamespacedName(&ent)),
+			Ports: []corev1.ServicePort{
+				{Name: "https", Port: HTTPPort, TargetPort: intstr.FromInt(targetPort)},
+			},
+		},
+	}
+
+	var reconciled corev1.Service
+	key := types.NamespacedName{Namespace: expected.Namespace, Name: expected.Name}
+	if err := reconciler.ReconcileResource(client, key, &expected, &reconciled, serviceManagedFields); err != nil {
+		return corev1.Service{}, err
+	}
+	return reconciled, nil
+}
@@ -0,0 +1,19 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package enterprisesearch
+
+import (
+	"fmt"
+
+	entv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1beta1"
+)
+
+// validate performs basic sanity checks on the Enterprise Search spec before reconciling any resource.
+func validate(ent entv1beta1.EnterpriseSearch) error {
+	if len(ent.Spec.Version) == 0 {
+		return fmt.Errorf("spec.version: Invalid value %q: version is required", ent.Spec.Version)
+	}
+	return nil
+}
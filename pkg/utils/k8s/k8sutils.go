@@ -0,0 +1,72 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package k8s wraps the controller-runtime client with a few conveniences used across ECK controllers.
+package k8s
+
+import (
+	"context"
+
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Scheme is shared by every ECK controller and test helper. Each API group registers its own types into
+// it from its package's init(), so that a single scheme always knows about every CRD the operator manages.
+var Scheme = clientgoscheme.Scheme
+
+// Client is a simplified, context-less variant of the controller-runtime client used by ECK reconcilers.
+type Client interface {
+	Get(key types.NamespacedName, obj runtime.Object) error
+	List(list runtime.Object, opts ...client.ListOption) error
+	Create(obj runtime.Object) error
+	Update(obj runtime.Object) error
+	Delete(obj runtime.Object, opts ...client.DeleteOption) error
+	Status() client.StatusWriter
+}
+
+// wrappedClient adapts a controller-runtime client.Client to the context-less Client interface above.
+type wrappedClient struct {
+	client.Client
+}
+
+func (w wrappedClient) Get(key types.NamespacedName, obj runtime.Object) error {
+	return w.Client.Get(context.Background(), key, obj)
+}
+
+func (w wrappedClient) List(list runtime.Object, opts ...client.ListOption) error {
+	return w.Client.List(context.Background(), list, opts...)
+}
+
+func (w wrappedClient) Create(obj runtime.Object) error {
+	return w.Client.Create(context.Background(), obj)
+}
+
+func (w wrappedClient) Update(obj runtime.Object) error {
+	return w.Client.Update(context.Background(), obj)
+}
+
+func (w wrappedClient) Delete(obj runtime.Object, opts ...client.DeleteOption) error {
+	return w.Client.Delete(context.Background(), obj, opts...)
+}
+
+// WrapClient adapts an existing controller-runtime client.Client (e.g. the one from a Manager) to Client.
+func WrapClient(c client.Client) Client {
+	return wrappedClient{Client: c}
+}
+
+// WrappedFakeClient returns a Client backed by a controller-runtime fake client, for use in tests.
+func WrappedFakeClient(initObjs ...runtime.Object) Client {
+	return WrapClient(fake.NewFakeClientWithScheme(Scheme, initObjs...))
+}
+
+// ExtractNamespacedName returns the NamespacedName of the given object.
+func ExtractNamespacedName(obj metav1.Object) types.NamespacedName {
+	return types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+}